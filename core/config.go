@@ -0,0 +1,43 @@
+package core
+
+import (
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds the defaults an .everyonenorth.yaml file in a repo root can
+// set, so recurring options like exclude patterns don't need to be passed on
+// the command line every run.
+type Config struct {
+	Format   string `yaml:"format"`
+	Template string `yaml:"template"`
+	Filter   Filter `yaml:"filter"`
+}
+
+// LoadConfig reads and parses an .everyonenorth.yaml config file. A missing
+// file is not an error; it yields a zero-value Config.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Config{}, nil
+		}
+		return nil, err
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// SaveConfig writes cfg to path as YAML.
+func SaveConfig(path string, cfg *Config) error {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -2,15 +2,12 @@ package core
 
 import (
 	"bufio"
+	"context"
 	"embed"
 	"fmt"
 	"net/url"
 	"os"
-	"os/exec"
 	"strings"
-	"text/template"
-
-	"github.com/gkwa/everyonenorth/util"
 )
 
 //go:embed author_template.tmpl
@@ -18,74 +15,206 @@ var templateFS embed.FS
 
 type Author struct {
 	Name        string
+	Email       string
 	CommitCount string
 	RepoName    string
 	SearchURL   string
+	Login       string
+	AvatarURL   string
+	ProfileURL  string
 }
 
-func Run(cwd string) {
-	repoName, err := getRepoName(cwd)
-	if err != nil {
-		fmt.Fprintln(os.Stderr, "Error getting repository name:", err)
+// Options controls how Run renders and filters the authors list.
+type Options struct {
+	Format   string
+	Template string
+	Filter   Filter
+}
+
+func Run(cwd string, opts Options) {
+	if err := RunLocal(cwd, opts); err != nil {
+		fmt.Fprintln(os.Stderr, err)
 		return
 	}
+	fmt.Println("Authors file generated successfully.")
+}
+
+// RunLocal generates the authors file for the git working tree at cwd.
+func RunLocal(cwd string, opts Options) error {
+	opts = mergeConfigDefaults(cwd, opts)
 
-	currentBranch, err := getCurrentBranch(cwd)
+	_, authors, err := collectAuthors(context.Background(), NewExecBackend(cwd), opts, cwd)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, "Error getting current branch:", err)
-		return
+		return err
 	}
 
-	output, err := executeGitShortlog(cwd, currentBranch)
+	return renderToFile(OutputFilename(opts.Format), authors, opts)
+}
+
+// mergeConfigDefaults fills in zero-value fields of opts from an
+// .everyonenorth.yaml config file in cwd, if one exists.
+func mergeConfigDefaults(cwd, opts Options) Options {
+	cfg, err := LoadConfig(cwd + "/.everyonenorth.yaml")
 	if err != nil {
-		fmt.Fprintln(os.Stderr, "Error executing git shortlog:", err)
-		return
+		return opts
 	}
 
-	authors, err := parseLogOutput(output)
+	if opts.Format == "" {
+		opts.Format = cfg.Format
+	}
+	if opts.Template == "" {
+		opts.Template = cfg.Template
+	}
+	if len(opts.Filter.ExcludePatterns) == 0 {
+		opts.Filter.ExcludePatterns = cfg.Filter.ExcludePatterns
+	}
+	if opts.Filter.IncludeBots == nil {
+		opts.Filter.IncludeBots = cfg.Filter.IncludeBots
+	}
+	if opts.Filter.MailmapPath == "" {
+		opts.Filter.MailmapPath = cfg.Filter.MailmapPath
+	}
+
+	return opts
+}
+
+// collectAuthors runs the shortlog/filter/enrich pipeline against backend and
+// returns the repo name and the resulting authors, without writing anything
+// to disk. cwd is used to locate the default <cwd>/.mailmap when
+// opts.Filter.MailmapPath is empty (an explicit MailmapPath is opened as
+// given), and may be empty for backends with no local working tree (e.g. an
+// in-memory clone). ctx
+// bounds the host API calls host enrichment makes, so a caller with its own
+// deadline (RunRemote, RunMany) can cancel a stuck run.
+func collectAuthors(ctx context.Context, backend GitBackend, opts Options, cwd string) (repoName string, authors []Author, err error) {
+	repoURL, err := backend.RemoteURL()
 	if err != nil {
-		fmt.Fprintln(os.Stderr, "Error parsing log output:", err)
-		return
+		return "", nil, fmt.Errorf("error getting repository name: %v", err)
 	}
 
-	authorsWithURL := generateSearchURLs(authors, repoName)
+	repoName, err = repoNameFromURL(repoURL)
+	if err != nil {
+		return "", nil, fmt.Errorf("error getting repository name: %v", err)
+	}
 
-	err = writeMarkdownFile("authors.md", authorsWithURL)
+	currentBranch, err := backend.CurrentBranch()
 	if err != nil {
-		fmt.Fprintln(os.Stderr, "Error writing markdown file:", err)
-		return
+		return "", nil, fmt.Errorf("error getting current branch: %v", err)
+	}
+
+	output, err := backend.Shortlog(currentBranch)
+	if err != nil {
+		return "", nil, fmt.Errorf("error executing git shortlog: %v", err)
+	}
+
+	mailmap, err := loadMailmap(opts.Filter.MailmapPath, cwd)
+	if err != nil {
+		return "", nil, fmt.Errorf("error loading .mailmap: %v", err)
+	}
+
+	authors, err = parseLogOutput(output, mailmap)
+	if err != nil {
+		return "", nil, fmt.Errorf("error parsing log output: %v", err)
+	}
+
+	authors, err = filterAuthors(authors, opts.Filter)
+	if err != nil {
+		return "", nil, fmt.Errorf("error applying author filters: %v", err)
+	}
+
+	authorsWithURL := generateSearchURLs(authors, repoName)
+	if formatRendersEnrichment(opts.Format) {
+		authorsWithURL = enrichAuthors(ctx, authorsWithURL, repoURL)
 	}
 
-	fmt.Println("Markdown file generated successfully.")
+	return repoName, authorsWithURL, nil
+}
+
+// formatRendersEnrichment reports whether format's renderer displays the
+// Login/AvatarURL/ProfileURL fields host enrichment fills in. Markdown (the
+// default) doesn't, so skip the host API calls a plain local run would
+// otherwise make and discard.
+func formatRendersEnrichment(format string) bool {
+	switch format {
+	case "html", "json", "csv":
+		return true
+	default:
+		return false
+	}
 }
 
-func executeGitShortlog(cwd, currentBranch string) (string, error) {
-	cmd := exec.Command("git", "-C", cwd, "-c", "core.excludesFile=", "shortlog", "--summary", "--numbered", currentBranch)
-	output, exitCode, err := util.RunCommand(cmd, cwd)
+// renderToFile renders authors using the format/template in opts and writes
+// the result to filename.
+func renderToFile(filename string, authors []Author, opts Options) error {
+	renderer, err := NewRenderer(opts.Format, opts.Template)
 	if err != nil {
-		return "", fmt.Errorf("error executing git shortlog: %v\nExit code: %d\nOutput: %s", err, exitCode, output)
+		return fmt.Errorf("error selecting renderer: %v", err)
 	}
-	return output, nil
+
+	if err := writeOutputFile(filename, authors, renderer); err != nil {
+		return fmt.Errorf("error writing authors file: %v", err)
+	}
+
+	return nil
 }
 
-func parseLogOutput(output string) ([]Author, error) {
-	var authors []Author
+// parseLogOutput parses `git shortlog --summary --numbered --email` output,
+// canonicalizing each identity through mailmap and summing counts for
+// identities that collapse to the same canonical name (this also merges the
+// separate author/co-author groups produced by --group=trailer:co-authored-by).
+func parseLogOutput(output string, mailmap []mailmapEntry) ([]Author, error) {
+	counts := make(map[string]int)
+	emails := make(map[string]string)
+	var order []string
+
 	scanner := bufio.NewScanner(strings.NewReader(output))
 	for scanner.Scan() {
 		line := scanner.Text()
 		fields := strings.Fields(line)
-		if len(fields) >= 2 {
-			authorName := strings.Join(fields[1:], " ")
-			author := Author{
-				Name:        authorName,
-				CommitCount: fields[0],
-			}
-			authors = append(authors, author)
+		if len(fields) < 2 {
+			continue
+		}
+
+		count := 0
+		if _, err := fmt.Sscanf(fields[0], "%d", &count); err != nil {
+			continue
+		}
+
+		name, email := splitNameEmail(strings.Join(fields[1:], " "))
+		canonical := canonicalName(mailmap, name, email)
+
+		if _, ok := counts[canonical]; !ok {
+			order = append(order, canonical)
+			emails[canonical] = email
 		}
+		counts[canonical] += count
+	}
+
+	authors := make([]Author, 0, len(order))
+	for _, name := range order {
+		authors = append(authors, Author{
+			Name:        name,
+			Email:       emails[name],
+			CommitCount: fmt.Sprintf("%d", counts[name]),
+		})
 	}
 	return authors, nil
 }
 
+// splitNameEmail splits a shortlog "Name <email>" identity into its parts.
+// If no "<...>" is present, the whole string is treated as the name.
+func splitNameEmail(nameEmail string) (name, email string) {
+	start := strings.Index(nameEmail, "<")
+	end := strings.LastIndex(nameEmail, ">")
+	if start == -1 || end == -1 || end < start {
+		return strings.TrimSpace(nameEmail), ""
+	}
+
+	name = strings.TrimSpace(nameEmail[:start])
+	email = strings.TrimSpace(nameEmail[start+1 : end])
+	return name, email
+}
+
 func generateSearchURLs(authors []Author, repoName string) []Author {
 	var authorsWithURL []Author
 	for _, author := range authors {
@@ -103,7 +232,7 @@ func generateSearchURLs(authors []Author, repoName string) []Author {
 	return authorsWithURL
 }
 
-func writeMarkdownFile(filename string, authors []Author) error {
+func writeOutputFile(filename string, authors []Author, renderer Renderer) error {
 	file, err := os.Create(filename)
 	if err != nil {
 		return fmt.Errorf("error creating file: %v", err)
@@ -111,32 +240,14 @@ func writeMarkdownFile(filename string, authors []Author) error {
 	defer file.Close()
 
 	writer := bufio.NewWriter(file)
-	tmpl, err := template.ParseFS(templateFS, "author_template.tmpl")
-	if err != nil {
-		return fmt.Errorf("error parsing template: %v", err)
-	}
-
-	for _, author := range authors {
-		err := tmpl.Execute(writer, author)
-		if err != nil {
-			return fmt.Errorf("error executing template: %v", err)
-		}
-		_, err = writer.WriteString("\n")
-		if err != nil {
-			return fmt.Errorf("can't write newline to file: %w", err)
-		}
+	if err := renderer.Render(writer, authors); err != nil {
+		return err
 	}
 
-	writer.Flush()
-	return nil
+	return writer.Flush()
 }
 
-func getRepoName(cwd string) (string, error) {
-	repoURL, err := getRepoURL(cwd)
-	if err != nil {
-		return "", err
-	}
-
+func repoNameFromURL(repoURL string) (string, error) {
 	if isSSHURL(repoURL) {
 		return getRepoNameFromSSHURL(repoURL)
 	}
@@ -144,20 +255,6 @@ func getRepoName(cwd string) (string, error) {
 	return getRepoNameFromHTTPSURL(repoURL)
 }
 
-func getRepoURL(cwd string) (string, error) {
-	cmd := exec.Command(
-		"git",
-		"-C", cwd,
-		"config", "--get", "remote.origin.url",
-	)
-	output, exitCode, err := util.RunCommand(cmd, cwd)
-	if err != nil {
-		return "", fmt.Errorf("failed to get repository name: %v\nExit code: %d\nOutput: %s", err, exitCode, output)
-	}
-
-	return strings.TrimSpace(output), nil
-}
-
 func isSSHURL(repoURL string) bool {
 	return strings.HasPrefix(repoURL, "git@")
 }
@@ -189,17 +286,35 @@ func extractRepoName(repoPath string) string {
 	return repoName
 }
 
-func getCurrentBranch(gitDir string) (string, error) {
-	cmd := exec.Command(
-		"git",
-		"-C", gitDir,
-		"rev-parse", "--abbrev-ref", "HEAD",
-	)
-	output, exitCode, err := util.RunCommand(cmd, gitDir)
-	if err != nil {
-		return "", fmt.Errorf("failed to get current branch: %v\nExit code: %d\nOutput: %s", err, exitCode, output)
+// extractOwnerAndRepo returns the owner and repo name from a "owner/repo"
+// style path, stripping a trailing ".git" suffix. It returns an error if
+// repoPath doesn't contain at least two path segments.
+func extractOwnerAndRepo(repoPath string) (owner, repo string, err error) {
+	repoPath = strings.Trim(repoPath, "/")
+	parts := strings.Split(repoPath, "/")
+	if len(parts) < 2 {
+		return "", "", fmt.Errorf("can't determine owner/repo from path: %s", repoPath)
+	}
+	owner = parts[len(parts)-2]
+	repo = strings.TrimSuffix(parts[len(parts)-1], ".git")
+	return owner, repo, nil
+}
+
+// ownerAndRepoFromURL parses owner and repo name out of an SSH or HTTPS
+// remote URL, e.g. "git@github.com:gkwa/everyonenorth.git" or
+// "https://github.com/gkwa/everyonenorth".
+func ownerAndRepoFromURL(repoURL string) (owner, repo string, err error) {
+	if isSSHURL(repoURL) {
+		parts := strings.Split(repoURL, ":")
+		if len(parts) != 2 {
+			return "", "", fmt.Errorf("invalid SSH URL format: %s", repoURL)
+		}
+		return extractOwnerAndRepo(parts[1])
 	}
 
-	currentBranch := strings.TrimSpace(output)
-	return currentBranch, nil
+	parsedURL, err := url.Parse(repoURL)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to parse repository URL: %v", err)
+	}
+	return extractOwnerAndRepo(parsedURL.Path)
 }
@@ -0,0 +1,244 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// enrichHTTPClient is shared by the GitHub/GitLab enrichers. An explicit
+// timeout keeps a slow/unresponsive host from hanging a run indefinitely;
+// callers should still pass a ctx with its own deadline/cancellation.
+var enrichHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// authorQueryKey returns the identity to query the host API with, preferring
+// email since GitHub/GitLab's commit-author filter matches a login or email,
+// never a free-text display name.
+func authorQueryKey(author Author) string {
+	if author.Email != "" {
+		return author.Email
+	}
+	return author.Name
+}
+
+// AuthorEnricher resolves a shortlog author to a real host account.
+type AuthorEnricher interface {
+	Enrich(ctx context.Context, author Author) (Author, error)
+}
+
+// newAuthorEnricher returns the enricher for repoURL's host, and false if the
+// host isn't recognized.
+func newAuthorEnricher(repoURL string) (AuthorEnricher, bool) {
+	owner, repo, err := ownerAndRepoFromURL(repoURL)
+	if err != nil {
+		return nil, false
+	}
+
+	cache := loadEnrichCache(enrichCachePath())
+
+	switch {
+	case strings.Contains(repoURL, "github.com"):
+		return &githubEnricher{owner: owner, repo: repo, token: os.Getenv("GITHUB_TOKEN"), cache: cache}, true
+	case strings.Contains(repoURL, "gitlab.com"):
+		return &gitlabEnricher{owner: owner, repo: repo, token: os.Getenv("GITLAB_TOKEN"), cache: cache}, true
+	default:
+		return nil, false
+	}
+}
+
+// enrichAuthors resolves each author's login, avatar, and profile URL from
+// the repository's host API when possible, leaving the Google image search
+// fallback URL in place on any failure. ctx bounds and can cancel the whole
+// lookup pass.
+func enrichAuthors(ctx context.Context, authors []Author, repoURL string) []Author {
+	enricher, ok := newAuthorEnricher(repoURL)
+	if !ok {
+		return authors
+	}
+
+	for i, author := range authors {
+		enriched, err := enricher.Enrich(ctx, author)
+		if err != nil {
+			continue
+		}
+		authors[i] = enriched
+	}
+
+	return authors
+}
+
+type githubEnricher struct {
+	owner, repo, token string
+	cache              *enrichCache
+}
+
+func (e *githubEnricher) Enrich(ctx context.Context, author Author) (Author, error) {
+	queryKey := authorQueryKey(author)
+	cacheKey := "github:" + e.owner + "/" + e.repo + ":" + queryKey
+	if entry, ok := e.cache.get(cacheKey); ok {
+		return applyCacheEntry(author, entry), nil
+	}
+
+	reqURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/commits", e.owner, e.repo)
+	query := url.Values{"author": []string{queryKey}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return author, err
+	}
+	if e.token != "" {
+		req.Header.Set("Authorization", "Bearer "+e.token)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := enrichHTTPClient.Do(req)
+	if err != nil {
+		return author, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return author, fmt.Errorf("github api returned status %d", resp.StatusCode)
+	}
+
+	var commits []struct {
+		Author struct {
+			Login     string `json:"login"`
+			AvatarURL string `json:"avatar_url"`
+			HTMLURL   string `json:"html_url"`
+		} `json:"author"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&commits); err != nil {
+		return author, err
+	}
+	if len(commits) == 0 || commits[0].Author.Login == "" {
+		return author, fmt.Errorf("no github author found for %q", author.Name)
+	}
+
+	entry := cacheEntry{
+		Login:      commits[0].Author.Login,
+		AvatarURL:  commits[0].Author.AvatarURL,
+		ProfileURL: commits[0].Author.HTMLURL,
+	}
+	e.cache.set(cacheKey, entry)
+	e.cache.save()
+
+	return applyCacheEntry(author, entry), nil
+}
+
+type gitlabEnricher struct {
+	owner, repo, token string
+	cache              *enrichCache
+}
+
+func (e *gitlabEnricher) Enrich(ctx context.Context, author Author) (Author, error) {
+	queryKey := authorQueryKey(author)
+	cacheKey := "gitlab:" + e.owner + "/" + e.repo + ":" + queryKey
+	if entry, ok := e.cache.get(cacheKey); ok {
+		return applyCacheEntry(author, entry), nil
+	}
+
+	project := url.PathEscape(e.owner + "/" + e.repo)
+	reqURL := fmt.Sprintf("https://gitlab.com/api/v4/projects/%s/repository/commits", project)
+	query := url.Values{"author": []string{queryKey}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return author, err
+	}
+	if e.token != "" {
+		req.Header.Set("PRIVATE-TOKEN", e.token)
+	}
+
+	resp, err := enrichHTTPClient.Do(req)
+	if err != nil {
+		return author, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return author, fmt.Errorf("gitlab api returned status %d", resp.StatusCode)
+	}
+
+	var commits []struct {
+		AuthorName  string `json:"author_name"`
+		AuthorEmail string `json:"author_email"`
+		WebURL      string `json:"web_url"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&commits); err != nil {
+		return author, err
+	}
+	if len(commits) == 0 {
+		return author, fmt.Errorf("no gitlab commits found for %q", author.Name)
+	}
+
+	entry, err := e.resolveUser(ctx, commits[0].AuthorEmail)
+	if err != nil {
+		return author, err
+	}
+	e.cache.set(cacheKey, entry)
+	e.cache.save()
+
+	return applyCacheEntry(author, entry), nil
+}
+
+// resolveUser looks up the real GitLab user behind a commit author email via
+// the Users API, since the commits API only echoes back the free-text
+// author_name/author_email recorded in the commit, not an account.
+func (e *gitlabEnricher) resolveUser(ctx context.Context, email string) (cacheEntry, error) {
+	reqURL := "https://gitlab.com/api/v4/users"
+	query := url.Values{"search": []string{email}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return cacheEntry{}, err
+	}
+	if e.token != "" {
+		req.Header.Set("PRIVATE-TOKEN", e.token)
+	}
+
+	resp, err := enrichHTTPClient.Do(req)
+	if err != nil {
+		return cacheEntry{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return cacheEntry{}, fmt.Errorf("gitlab api returned status %d", resp.StatusCode)
+	}
+
+	var users []struct {
+		Username  string `json:"username"`
+		AvatarURL string `json:"avatar_url"`
+		WebURL    string `json:"web_url"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&users); err != nil {
+		return cacheEntry{}, err
+	}
+	if len(users) == 0 {
+		return cacheEntry{}, fmt.Errorf("no gitlab user found for %q", email)
+	}
+
+	return cacheEntry{
+		Login:      users[0].Username,
+		AvatarURL:  users[0].AvatarURL,
+		ProfileURL: users[0].WebURL,
+	}, nil
+}
+
+func applyCacheEntry(author Author, entry cacheEntry) Author {
+	author.Login = entry.Login
+	author.AvatarURL = entry.AvatarURL
+	author.ProfileURL = entry.ProfileURL
+	return author
+}
+
+func enrichCachePath() string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return ".everyonenorth-cache.json"
+	}
+	return cacheDir + "/everyonenorth/enrich-cache.json"
+}
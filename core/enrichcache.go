@@ -0,0 +1,54 @@
+package core
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+type cacheEntry struct {
+	Login      string `json:"login"`
+	AvatarURL  string `json:"avatar_url"`
+	ProfileURL string `json:"profile_url"`
+}
+
+// enrichCache persists AuthorEnricher lookups to disk so repeated runs don't
+// re-hit the GitHub/GitLab API for authors we've already resolved.
+type enrichCache struct {
+	path    string
+	entries map[string]cacheEntry
+}
+
+func loadEnrichCache(path string) *enrichCache {
+	c := &enrichCache{path: path, entries: make(map[string]cacheEntry)}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return c
+	}
+
+	_ = json.Unmarshal(data, &c.entries)
+	return c
+}
+
+func (c *enrichCache) get(key string) (cacheEntry, bool) {
+	entry, ok := c.entries[key]
+	return entry, ok
+}
+
+func (c *enrichCache) set(key string, entry cacheEntry) {
+	c.entries[key] = entry
+}
+
+func (c *enrichCache) save() error {
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(c.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(c.path, data, 0o644)
+}
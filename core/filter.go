@@ -0,0 +1,54 @@
+package core
+
+import "regexp"
+
+// Filter controls which shortlog authors are excluded from the report.
+type Filter struct {
+	ExcludePatterns []string `yaml:"excludePatterns"`
+	// IncludeBots is a *bool rather than bool so mergeConfigDefaults can tell
+	// "caller didn't set this" (nil) apart from "caller explicitly set false",
+	// letting an explicit false override a config file's includeBots: true.
+	IncludeBots *bool  `yaml:"includeBots"`
+	MailmapPath string `yaml:"mailmapPath"`
+}
+
+// defaultBotPattern matches the common "name[bot]" convention used by
+// dependabot, github-actions, and similar CI identities.
+var defaultBotPattern = regexp.MustCompile(`\[bot\]$`)
+
+// filterAuthors drops authors matching filter.ExcludePatterns, and bot
+// identities unless filter.IncludeBots is set.
+func filterAuthors(authors []Author, filter Filter) ([]Author, error) {
+	patterns := make([]*regexp.Regexp, 0, len(filter.ExcludePatterns))
+	for _, p := range filter.ExcludePatterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, re)
+	}
+
+	includeBots := filter.IncludeBots != nil && *filter.IncludeBots
+
+	var filtered []Author
+	for _, author := range authors {
+		if !includeBots && defaultBotPattern.MatchString(author.Name) {
+			continue
+		}
+
+		excluded := false
+		for _, re := range patterns {
+			if re.MatchString(author.Name) {
+				excluded = true
+				break
+			}
+		}
+		if excluded {
+			continue
+		}
+
+		filtered = append(filtered, author)
+	}
+
+	return filtered, nil
+}
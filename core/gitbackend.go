@@ -0,0 +1,167 @@
+package core
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/gkwa/everyonenorth/util"
+)
+
+// coAuthoredByTrailer matches a "Co-authored-by: Name <email>" commit message
+// trailer, mirroring the identities git shortlog --group=trailer:co-authored-by
+// pulls out of the same commits.
+var coAuthoredByTrailer = regexp.MustCompile(`(?mi)^Co-authored-by:\s*(.+?)\s*<(.+?)>\s*$`)
+
+// GitBackend abstracts the repository operations Run needs so the shortlog,
+// current branch, and remote URL can be sourced from a local git binary or
+// from an in-process git implementation.
+type GitBackend interface {
+	Shortlog(currentBranch string) (string, error)
+	CurrentBranch() (string, error)
+	RemoteURL() (string, error)
+}
+
+// ExecBackend shells out to the git binary on PATH.
+type ExecBackend struct {
+	cwd string
+}
+
+func NewExecBackend(cwd string) *ExecBackend {
+	return &ExecBackend{cwd: cwd}
+}
+
+func (b *ExecBackend) Shortlog(currentBranch string) (string, error) {
+	cmd := exec.Command("git", "-C", b.cwd, "-c", "core.excludesFile=",
+		"shortlog", "--summary", "--numbered", "--email", "--group=author", "--group=trailer:co-authored-by",
+		currentBranch)
+	output, exitCode, err := util.RunCommand(cmd, b.cwd)
+	if err != nil {
+		return "", fmt.Errorf("error executing git shortlog: %v\nExit code: %d\nOutput: %s", err, exitCode, output)
+	}
+	return output, nil
+}
+
+func (b *ExecBackend) CurrentBranch() (string, error) {
+	cmd := exec.Command("git", "-C", b.cwd, "rev-parse", "--abbrev-ref", "HEAD")
+	output, exitCode, err := util.RunCommand(cmd, b.cwd)
+	if err != nil {
+		return "", fmt.Errorf("failed to get current branch: %v\nExit code: %d\nOutput: %s", err, exitCode, output)
+	}
+	return strings.TrimSpace(output), nil
+}
+
+func (b *ExecBackend) RemoteURL() (string, error) {
+	cmd := exec.Command("git", "-C", b.cwd, "config", "--get", "remote.origin.url")
+	output, exitCode, err := util.RunCommand(cmd, b.cwd)
+	if err != nil {
+		return "", fmt.Errorf("failed to get repository name: %v\nExit code: %d\nOutput: %s", err, exitCode, output)
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// GoGitBackend reads the repository in-process via go-git, so no git binary
+// needs to be present on PATH.
+type GoGitBackend struct {
+	repo *git.Repository
+}
+
+func NewGoGitBackend(cwd string) (*GoGitBackend, error) {
+	repo, err := git.PlainOpen(cwd)
+	if err != nil {
+		return nil, fmt.Errorf("error opening repository: %v", err)
+	}
+	return &GoGitBackend{repo: repo}, nil
+}
+
+// NewGoGitBackendFromRepo wraps an already-opened repository, letting callers
+// build a backend around a clone that never touched disk.
+func NewGoGitBackendFromRepo(repo *git.Repository) *GoGitBackend {
+	return &GoGitBackend{repo: repo}
+}
+
+func (b *GoGitBackend) Shortlog(currentBranch string) (string, error) {
+	counts, err := b.authorCommitCounts(currentBranch)
+	if err != nil {
+		return "", err
+	}
+
+	type authorCount struct {
+		name  string
+		count int
+	}
+	counted := make([]authorCount, 0, len(counts))
+	for name, count := range counts {
+		counted = append(counted, authorCount{name, count})
+	}
+	sort.SliceStable(counted, func(i, j int) bool {
+		if counted[i].count != counted[j].count {
+			return counted[i].count > counted[j].count
+		}
+		return counted[i].name < counted[j].name
+	})
+
+	var sb strings.Builder
+	for _, ac := range counted {
+		fmt.Fprintf(&sb, "%6d\t%s\n", ac.count, ac.name)
+	}
+	return sb.String(), nil
+}
+
+// authorCommitCounts walks the commit log from branch, counting each commit
+// against both its author and any "Co-authored-by:" trailers in its message,
+// matching the identities git shortlog --group=author --group=trailer:co-authored-by
+// produces for the ExecBackend path.
+func (b *GoGitBackend) authorCommitCounts(branch string) (map[string]int, error) {
+	hash, err := b.repo.ResolveRevision(plumbing.Revision(branch))
+	if err != nil {
+		return nil, fmt.Errorf("error resolving revision %q: %v", branch, err)
+	}
+
+	commitIter, err := b.repo.Log(&git.LogOptions{From: *hash})
+	if err != nil {
+		return nil, fmt.Errorf("error walking commit log: %v", err)
+	}
+
+	counts := make(map[string]int)
+	err = commitIter.ForEach(func(c *object.Commit) error {
+		counts[fmt.Sprintf("%s <%s>", c.Author.Name, c.Author.Email)]++
+
+		for _, trailer := range coAuthoredByTrailer.FindAllStringSubmatch(c.Message, -1) {
+			counts[fmt.Sprintf("%s <%s>", trailer[1], trailer[2])]++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error aggregating commits: %v", err)
+	}
+	return counts, nil
+}
+
+func (b *GoGitBackend) CurrentBranch() (string, error) {
+	ref, err := b.repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("error resolving HEAD: %v", err)
+	}
+	return ref.Name().Short(), nil
+}
+
+func (b *GoGitBackend) RemoteURL() (string, error) {
+	remote, err := b.repo.Remote("origin")
+	if err != nil {
+		return "", fmt.Errorf("error getting remote origin: %v", err)
+	}
+
+	cfg := remote.Config()
+	if len(cfg.URLs) == 0 {
+		return "", fmt.Errorf("remote origin has no URLs configured")
+	}
+	return cfg.URLs[0], nil
+}
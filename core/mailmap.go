@@ -0,0 +1,92 @@
+package core
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// mailmapLine matches up to two "Name <email>" entries per .mailmap line:
+//
+//	Proper Name <proper@email.xx> Commit Name <commit@email.xx>
+//	Proper Name <proper@email.xx> <commit@email.xx>
+//	Proper Name <commit@email.xx>
+var mailmapLine = regexp.MustCompile(`^(?:([^<]*)<([^>]*)>\s*)(?:([^<]*)<([^>]*)>)?\s*$`)
+
+type mailmapEntry struct {
+	canonicalName string
+	commitName    string
+	commitEmail   string
+}
+
+// loadMailmap resolves the .mailmap to use for a run: an explicit path if
+// given, otherwise "<cwd>/.mailmap" if it exists. A missing mailmap is not an
+// error; canonicalization is simply skipped.
+func loadMailmap(mailmapPath, cwd string) ([]mailmapEntry, error) {
+	path := mailmapPath
+	if path == "" {
+		if cwd == "" {
+			return nil, nil
+		}
+		path = filepath.Join(cwd, ".mailmap")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []mailmapEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		matches := mailmapLine.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+
+		canonicalName := strings.TrimSpace(matches[1])
+		if matches[3] == "" && matches[4] == "" {
+			entries = append(entries, mailmapEntry{
+				canonicalName: canonicalName,
+				commitEmail:   strings.TrimSpace(matches[2]),
+			})
+			continue
+		}
+
+		entries = append(entries, mailmapEntry{
+			canonicalName: canonicalName,
+			commitName:    strings.TrimSpace(matches[3]),
+			commitEmail:   strings.TrimSpace(matches[4]),
+		})
+	}
+
+	return entries, scanner.Err()
+}
+
+// canonicalName returns the mailmap-canonical name for a shortlog identity,
+// or name unchanged if no entry matches.
+func canonicalName(entries []mailmapEntry, name, email string) string {
+	for _, e := range entries {
+		if e.commitEmail != "" && e.commitEmail != email {
+			continue
+		}
+		if e.commitName != "" && e.commitName != name {
+			continue
+		}
+		if e.canonicalName != "" {
+			return e.canonicalName
+		}
+	}
+	return name
+}
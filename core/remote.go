@@ -0,0 +1,155 @@
+package core
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// RunRemote generates the authors file for remoteURL without checking it out
+// to disk, cloning it into memory via go-git instead.
+func RunRemote(ctx context.Context, remoteURL string, opts Options) error {
+	repo, err := cloneInMemory(ctx, remoteURL)
+	if err != nil {
+		return err
+	}
+
+	_, authors, err := collectAuthors(ctx, NewGoGitBackendFromRepo(repo), opts, "")
+	if err != nil {
+		return err
+	}
+
+	return renderToFile(OutputFilename(opts.Format), authors, opts)
+}
+
+// repoAuthors pairs a repo's display name with its filtered author list, for
+// targets processed as part of a RunMany call.
+type repoAuthors struct {
+	repoName string
+	authors  []Author
+	opts     Options
+}
+
+// RunMany generates an authors report across a mix of local working tree
+// paths and remote repository URLs. With the markdown format (the default)
+// it writes a single combined "authors.md" with one section per repo;
+// otherwise it writes one output file per repo.
+func RunMany(ctx context.Context, targets []string, opts Options) error {
+	results := make([]repoAuthors, 0, len(targets))
+	for _, target := range targets {
+		var (
+			repoName   string
+			authors    []Author
+			targetOpts Options
+			err        error
+		)
+
+		if isRemoteURL(target) {
+			targetOpts = opts
+			repo, cloneErr := cloneInMemory(ctx, target)
+			if cloneErr != nil {
+				return fmt.Errorf("error processing %s: %v", target, cloneErr)
+			}
+			repoName, authors, err = collectAuthors(ctx, NewGoGitBackendFromRepo(repo), targetOpts, "")
+		} else {
+			targetOpts = mergeConfigDefaults(target, opts)
+			repoName, authors, err = collectAuthors(ctx, NewExecBackend(target), targetOpts, target)
+		}
+
+		if err != nil {
+			return fmt.Errorf("error processing %s: %v", target, err)
+		}
+
+		results = append(results, repoAuthors{repoName: repoName, authors: authors, opts: targetOpts})
+	}
+
+	if opts.Format == "" || opts.Format == "markdown" {
+		return writeCombinedMarkdown("authors.md", results, opts)
+	}
+
+	for _, r := range results {
+		filename := r.repoName + "-" + OutputFilename(r.opts.Format)
+		if err := renderToFile(filename, r.authors, r.opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeCombinedMarkdown(filename string, results []repoAuthors, opts Options) error {
+	renderer, err := NewRenderer("markdown", opts.Template)
+	if err != nil {
+		return fmt.Errorf("error selecting renderer: %v", err)
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("error creating file: %v", err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	for _, r := range results {
+		if _, err := fmt.Fprintf(writer, "## %s\n\n", r.repoName); err != nil {
+			return err
+		}
+		if err := renderer.Render(writer, r.authors); err != nil {
+			return err
+		}
+		if _, err := writer.WriteString("\n"); err != nil {
+			return err
+		}
+	}
+
+	return writer.Flush()
+}
+
+func isRemoteURL(target string) bool {
+	return strings.HasPrefix(target, "git@") ||
+		strings.HasPrefix(target, "http://") ||
+		strings.HasPrefix(target, "https://")
+}
+
+// cloneInMemory clones remoteURL into an in-memory storage backend, so
+// generating an authors list for a public repo never touches disk.
+func cloneInMemory(ctx context.Context, remoteURL string) (*git.Repository, error) {
+	auth, err := authForURL(remoteURL)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving auth for %s: %v", remoteURL, err)
+	}
+
+	repo, err := git.CloneContext(ctx, memory.NewStorage(), nil, &git.CloneOptions{
+		URL:  remoteURL,
+		Auth: auth,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error cloning %s: %v", remoteURL, err)
+	}
+
+	return repo, nil
+}
+
+// authForURL resolves SSH public-key auth for git@ remotes, mirroring the
+// auth selection used when a URL indicates SSH rather than HTTPS. HTTPS
+// remotes return nil auth, relying on anonymous/public access.
+func authForURL(remoteURL string) (transport.AuthMethod, error) {
+	if !strings.HasPrefix(remoteURL, "git@") {
+		return nil, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	keyPath := filepath.Join(home, ".ssh", "id_rsa")
+	return ssh.NewPublicKeysFromFile("git", keyPath, "")
+}
@@ -0,0 +1,137 @@
+package core
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"text/template"
+)
+
+// Renderer writes a list of authors to w in some output format.
+type Renderer interface {
+	Render(w io.Writer, authors []Author) error
+}
+
+// NewRenderer returns the Renderer for format, or an error if format is
+// unrecognized. An empty templatePath uses the embedded default template
+// for the markdown format and is ignored by the others.
+func NewRenderer(format, templatePath string) (Renderer, error) {
+	switch format {
+	case "", "markdown":
+		return newMarkdownRenderer(templatePath)
+	case "html":
+		return &htmlRenderer{}, nil
+	case "json":
+		return &jsonRenderer{}, nil
+	case "csv":
+		return &csvRenderer{}, nil
+	default:
+		return nil, fmt.Errorf("unknown format: %s", format)
+	}
+}
+
+// OutputFilename returns the authors-list filename conventionally used for
+// format, e.g. "authors.html" for "html".
+func OutputFilename(format string) string {
+	switch format {
+	case "html":
+		return "authors.html"
+	case "json":
+		return "authors.json"
+	case "csv":
+		return "authors.csv"
+	default:
+		return "authors.md"
+	}
+}
+
+type markdownRenderer struct {
+	tmpl *template.Template
+}
+
+func newMarkdownRenderer(templatePath string) (*markdownRenderer, error) {
+	if templatePath != "" {
+		tmpl, err := template.ParseFiles(templatePath)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing template: %v", err)
+		}
+		return &markdownRenderer{tmpl: tmpl}, nil
+	}
+
+	tmpl, err := template.ParseFS(templateFS, "author_template.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("error parsing template: %v", err)
+	}
+	return &markdownRenderer{tmpl: tmpl}, nil
+}
+
+func (r *markdownRenderer) Render(w io.Writer, authors []Author) error {
+	for _, author := range authors {
+		if err := r.tmpl.Execute(w, author); err != nil {
+			return fmt.Errorf("error executing template: %v", err)
+		}
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return fmt.Errorf("can't write newline to file: %w", err)
+		}
+	}
+	return nil
+}
+
+type htmlRenderer struct{}
+
+func (r *htmlRenderer) Render(w io.Writer, authors []Author) error {
+	if _, err := io.WriteString(w, "<table>\n"); err != nil {
+		return err
+	}
+	for _, author := range authors {
+		imgSrc := author.AvatarURL
+		if imgSrc == "" {
+			imgSrc = author.SearchURL
+		}
+		profileURL := author.ProfileURL
+		if profileURL == "" {
+			profileURL = author.SearchURL
+		}
+
+		row := fmt.Sprintf(
+			"<tr><td>%s</td><td>%s</td><td>%s</td><td><a href=\"%s\"><img src=\"%s\" alt=\"%s\"></a></td></tr>\n",
+			html.EscapeString(author.Name), html.EscapeString(author.Login), html.EscapeString(author.CommitCount),
+			html.EscapeString(profileURL), html.EscapeString(imgSrc), html.EscapeString(author.Name),
+		)
+		if _, err := io.WriteString(w, row); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "</table>\n")
+	return err
+}
+
+type jsonRenderer struct{}
+
+func (r *jsonRenderer) Render(w io.Writer, authors []Author) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(authors)
+}
+
+type csvRenderer struct{}
+
+func (r *csvRenderer) Render(w io.Writer, authors []Author) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"Name", "Email", "CommitCount", "RepoName", "SearchURL", "Login", "AvatarURL", "ProfileURL"}); err != nil {
+		return err
+	}
+	for _, author := range authors {
+		record := []string{
+			author.Name, author.Email, author.CommitCount, author.RepoName, author.SearchURL,
+			author.Login, author.AvatarURL, author.ProfileURL,
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}